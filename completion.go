@@ -0,0 +1,188 @@
+package arg
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CompleteFunc computes the list of dynamic completions for a flag tagged
+// with `arg:"complete:name"`, where name is the value passed to
+// RegisterCompletionFunc. args holds the command line tokens seen so far
+// (not including the program name) and toComplete is the partial word the
+// user is currently typing.
+type CompleteFunc func(args []string, toComplete string) []string
+
+// completionFuncs holds the funcs registered with RegisterCompletionFunc,
+// keyed by the name given in the `complete:name` tag.
+var completionFuncs = map[string]CompleteFunc{}
+
+// RegisterCompletionFunc associates name with fn so that a field tagged
+// `arg:"complete:name"` can provide dynamic shell completions (for example,
+// completing filenames or the names of some remote resource) instead of
+// just the flag names themselves.
+func RegisterCompletionFunc(name string, fn CompleteFunc) {
+	completionFuncs[name] = fn
+}
+
+// Complete reports whether args is an invocation of the hidden __complete
+// subcommand emitted by the generated shell scripts, and if so returns the
+// list of completions for it. Programs that want dynamic completion support
+// should call this before Parse, e.g.:
+//
+//	if words, ok := parser.Complete(os.Args[1:]); ok {
+//		for _, w := range words {
+//			fmt.Println(w)
+//		}
+//		return
+//	}
+//	err := parser.Parse(os.Args[1:])
+func (p *Parser) Complete(args []string) ([]string, bool) {
+	if len(args) == 0 || args[0] != "__complete" {
+		return nil, false
+	}
+	args = args[1:]
+
+	toComplete := ""
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+	// drop the "--" the generated scripts put before the word being completed
+	if n := len(args); n > 0 && args[n-1] == "--" {
+		args = args[:n-1]
+	}
+
+	cmd := p.cmd
+	for _, a := range args {
+		if sub := findSubcommand(cmd.subcommands, a); sub != nil {
+			cmd = sub
+		}
+	}
+
+	var words []string
+	for _, spec := range cmd.specs {
+		if spec.positional || spec.hidden {
+			continue
+		}
+		if fn, ok := completeFuncFor(spec); ok && strings.HasPrefix(toComplete, "-") == false {
+			words = append(words, fn(args, toComplete)...)
+			continue
+		}
+		words = append(words, "--"+spec.long)
+		if spec.short != "" {
+			words = append(words, "-"+spec.short)
+		}
+	}
+	for _, sub := range cmd.subcommands {
+		words = append(words, sub.name)
+	}
+
+	var out []string
+	for _, w := range words {
+		if strings.HasPrefix(w, toComplete) {
+			out = append(out, w)
+		}
+	}
+	sort.Strings(out)
+	return out, true
+}
+
+// completeFuncFor looks up the CompleteFunc registered for spec's
+// `complete:name` tag, if any.
+func completeFuncFor(spec *spec) (CompleteFunc, bool) {
+	if spec.complete == "" {
+		return nil, false
+	}
+	fn, ok := completionFuncs[spec.complete]
+	return fn, ok
+}
+
+// GenBashCompletion writes a bash completion script for this program to w.
+func (p *Parser) GenBashCompletion(w io.Writer) error {
+	fmt.Fprintf(w, "# bash completion for %s\n", p.cmd.name)
+	fmt.Fprintf(w, "_%s_complete() {\n", p.cmd.name)
+	fmt.Fprintf(w, "\tlocal cur words\n")
+	fmt.Fprintf(w, "\tcur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	fmt.Fprintf(w, "\twords=$(%s __complete \"${COMP_WORDS[@]:1:COMP_CWORD-1}\" -- \"$cur\")\n", p.cmd.name)
+	fmt.Fprintf(w, "\tCOMPREPLY=( $(compgen -W \"$words\" -- \"$cur\") )\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "complete -F _%s_complete %s\n", p.cmd.name, p.cmd.name)
+	return nil
+}
+
+// GenZshCompletion writes a zsh completion script for this program to w. The
+// `help` tag, if present, is used as the description shown next to each
+// flag and subcommand.
+func (p *Parser) GenZshCompletion(w io.Writer) error {
+	fmt.Fprintf(w, "#compdef %s\n\n", p.cmd.name)
+	fmt.Fprintf(w, "_%s() {\n", p.cmd.name)
+	fmt.Fprintf(w, "\tlocal -a opts\n\topts=(\n")
+	writeZshSpecs(w, p.cmd)
+	fmt.Fprintf(w, "\t)\n\t_describe '%s' opts\n", p.cmd.name)
+	fmt.Fprintf(w, "}\n\n_%s \"$@\"\n", p.cmd.name)
+	return nil
+}
+
+func writeZshSpecs(w io.Writer, cmd *command) {
+	for _, spec := range cmd.specs {
+		if spec.positional || spec.hidden {
+			continue
+		}
+		fmt.Fprintf(w, "\t\t'--%s:%s'\n", spec.long, zshEscape(spec.help))
+		if spec.short != "" {
+			fmt.Fprintf(w, "\t\t'-%s:%s'\n", spec.short, zshEscape(spec.help))
+		}
+	}
+	for _, sub := range cmd.subcommands {
+		fmt.Fprintf(w, "\t\t'%s:%s'\n", sub.name, zshEscape(sub.help))
+	}
+}
+
+func zshEscape(s string) string {
+	return strings.ReplaceAll(s, "'", "'\\''")
+}
+
+// GenFishCompletion writes a fish completion script for this program to w.
+// If includeDesc is true, the `help` tag is rendered as the description
+// fish shows alongside each candidate.
+func (p *Parser) GenFishCompletion(w io.Writer, includeDesc bool) error {
+	writeFishSpecs(w, p.cmd.name, p.cmd, includeDesc)
+	return nil
+}
+
+func writeFishSpecs(w io.Writer, prog string, cmd *command, includeDesc bool) {
+	for _, spec := range cmd.specs {
+		if spec.positional || spec.hidden {
+			continue
+		}
+		fmt.Fprintf(w, "complete -c %s -l %s", prog, spec.long)
+		if spec.short != "" {
+			fmt.Fprintf(w, " -s %s", spec.short)
+		}
+		if includeDesc && spec.help != "" {
+			fmt.Fprintf(w, " -d %q", spec.help)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, sub := range cmd.subcommands {
+		fmt.Fprintf(w, "complete -c %s -n __fish_use_subcommand -a %s", prog, sub.name)
+		if includeDesc && sub.help != "" {
+			fmt.Fprintf(w, " -d %q", sub.help)
+		}
+		fmt.Fprintln(w)
+		writeFishSpecs(w, prog, sub, includeDesc)
+	}
+}
+
+// GenPowerShellCompletion writes a PowerShell completion script for this
+// program to w.
+func (p *Parser) GenPowerShellCompletion(w io.Writer) error {
+	fmt.Fprintf(w, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", p.cmd.name)
+	fmt.Fprintf(w, "\tparam($wordToComplete, $commandAst, $cursorPosition)\n")
+	fmt.Fprintf(w, "\t%s __complete $commandAst.ToString() -- $wordToComplete | ForEach-Object {\n", p.cmd.name)
+	fmt.Fprintf(w, "\t\t[System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	fmt.Fprintf(w, "\t}\n}\n")
+	return nil
+}
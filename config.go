@@ -0,0 +1,269 @@
+package arg
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	scalar "github.com/alexflint/go-scalar"
+)
+
+// Format identifies the syntax of a config file listed in Config.Files.
+type Format int
+
+const (
+	// FormatINI parses config files as INI: [section] headers followed by
+	// "key = value" lines. Values may be bare or double-quoted; a bare
+	// value is taken as is, and a quoted value has its surrounding pair of
+	// quotes removed, keeping any whitespace inside them.
+	FormatINI Format = iota
+	// FormatTOML parses config files as a simple subset of TOML: the same
+	// [section] and "key = value" syntax as FormatINI, but string values
+	// must be double-quoted, and a field backed by a slice can be given as
+	// a bracketed, comma-separated array, e.g. tags = ["a", "b"].
+	FormatTOML
+)
+
+// readConfigFile parses the [section]/"key = value" syntax shared by
+// FormatINI and FormatTOML into values, keyed by "section.key" (or just
+// "key" for entries outside any section). format controls how the value on
+// the right-hand side of "=" is interpreted: FormatINI strips one layer of
+// surrounding double quotes if present and otherwise takes the value
+// verbatim; FormatTOML requires string values to be double-quoted. Under
+// both formats, bracketed arrays are recognized and stored as a CSV
+// encoding of their elements so that setSlice can decode them later.
+func readConfigFile(r io.Reader, format Format, values map[string]string) error {
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		pos := strings.Index(line, "=")
+		if pos == -1 {
+			return fmt.Errorf("invalid line: %s", line)
+		}
+		key := strings.TrimSpace(line[:pos])
+		raw := strings.TrimSpace(line[pos+1:])
+		value, err := parseConfigValue(raw, format)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %v", key, err)
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+		values[key] = value
+	}
+	return scanner.Err()
+}
+
+// parseConfigValue interprets the right-hand side of a "key = value" line
+// according to format, returning a string ready to be fed to scalar.ParseValue
+// (or, for arrays, to csv.NewReader as setSlice expects elsewhere in this
+// package). Bracketed arrays, as WriteConfig emits for slice fields, are
+// recognized under both formats; what format actually controls is how bare
+// (non-array) values are read: FormatTOML requires them to be double-quoted
+// strings, while FormatINI takes them bare or strips one pair of surrounding
+// double quotes.
+func parseConfigValue(raw string, format Format) (string, error) {
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		var b strings.Builder
+		wcsv := csv.NewWriter(&b)
+		var elems []string
+		for _, elem := range strings.Split(raw[1:len(raw)-1], ",") {
+			elem = strings.TrimSpace(elem)
+			if elem == "" {
+				continue
+			}
+			unquoted, err := unquoteTOMLString(elem)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, unquoted)
+		}
+		if err := wcsv.Write(elems); err != nil {
+			return "", err
+		}
+		wcsv.Flush()
+		return strings.TrimRight(b.String(), "\n"), nil
+	}
+
+	if format == FormatTOML {
+		return unquoteTOMLString(raw)
+	}
+	if len(raw) >= 2 && strings.HasPrefix(raw, `"`) && strings.HasSuffix(raw, `"`) {
+		return raw[1 : len(raw)-1], nil
+	}
+	return raw, nil
+}
+
+// unquoteTOMLString requires raw to be a double-quoted string, as TOML
+// mandates, and returns its contents.
+func unquoteTOMLString(raw string) (string, error) {
+	if len(raw) < 2 || !strings.HasPrefix(raw, `"`) || !strings.HasSuffix(raw, `"`) {
+		return "", fmt.Errorf("TOML string values must be double-quoted, got %s", raw)
+	}
+	return raw[1 : len(raw)-1], nil
+}
+
+// configName returns the name Config.Files/WriteConfig use for spec, honoring
+// an `arg:"config:name"` tag override.
+func configName(spec *spec) string {
+	if spec.configKey != "" {
+		return spec.configKey
+	}
+	return spec.long
+}
+
+// setConfigCommand sets, on root, every field of cmd (and of its
+// subcommands, recursively) that has a matching entry in values, using the
+// same "section.key" naming WriteConfig produces.
+func setConfigCommand(root reflect.Value, cmd *command, values map[string]string) error {
+	for _, spec := range cmd.specs {
+		if spec.positional {
+			continue
+		}
+		key := configName(spec)
+		if cmd.parent != nil {
+			key = cmd.name + "." + key
+		}
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		fv := resolvePath(root, spec.dest)
+		if spec.multiple {
+			// an empty array ("[]") is stored as "", which csv cannot read
+			var elems []string
+			if value != "" {
+				var err error
+				elems, err = csv.NewReader(strings.NewReader(value)).Read()
+				if err != nil {
+					return fmt.Errorf("error processing config value for %s: %v", key, err)
+				}
+			}
+			if err := setSlice(fv, elems, true); err != nil {
+				return fmt.Errorf("error processing config value for %s: %v", key, err)
+			}
+			continue
+		}
+		if err := scalar.ParseValue(fv, value); err != nil {
+			return fmt.Errorf("error processing config value for %s: %v", key, err)
+		}
+	}
+
+	for _, sub := range cmd.subcommands {
+		// only allocate a subcommand struct, and recurse into it, when
+		// values actually has something for it (or one of its own
+		// subcommands); otherwise a config file that never mentions a
+		// subcommand would make every subcommand non-nil, breaking the
+		// args.Get != nil dispatch pattern for subcommands the user never
+		// selected
+		if !hasConfigValues(sub, values) {
+			continue
+		}
+		subv := resolvePath(root, sub.dest)
+		if subv.Kind() == reflect.Ptr && subv.IsNil() {
+			subv.Set(reflect.New(subv.Type().Elem()))
+		}
+		if err := setConfigCommand(root, sub, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasConfigValues reports whether values has an entry for any non-positional
+// field of cmd, or of any of its subcommands, recursively. setConfigCommand
+// uses this to decide whether a subcommand is even worth allocating.
+func hasConfigValues(cmd *command, values map[string]string) bool {
+	for _, spec := range cmd.specs {
+		if spec.positional {
+			continue
+		}
+		key := configName(spec)
+		if cmd.parent != nil {
+			key = cmd.name + "." + key
+		}
+		if _, ok := values[key]; ok {
+			return true
+		}
+	}
+	for _, sub := range cmd.subcommands {
+		if hasConfigValues(sub, values) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteConfig writes the parser's current values to w, with each field's
+// `help` tag rendered as a comment above its key, and section headers
+// derived from subcommand struct names, so the result can be fed straight
+// back in via Config.Files under either Format: every value is double-quoted
+// (required by FormatTOML, and accepted by FormatINI), and slice fields are
+// written as a bracketed, comma-separated array of quoted elements.
+func (p *Parser) WriteConfig(w io.Writer) error {
+	return writeConfigCommand(w, p, p.cmd)
+}
+
+func writeConfigCommand(w io.Writer, p *Parser, cmd *command) error {
+	if cmd.parent != nil {
+		fmt.Fprintf(w, "[%s]\n", cmd.name)
+
+		// a subcommand's struct pointer is nil until it is selected on the
+		// command line (or loaded from a config file); WriteConfig is
+		// normally called to produce a template before that happens, so
+		// allocate it temporarily to render its zero values, then restore
+		// it to nil so building a template has no lasting effect on the
+		// parser's destination structs
+		fv := resolvePath(p.roots[cmd.dest.root], cmd.dest)
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			defer fv.Set(reflect.Zero(fv.Type()))
+		}
+	}
+	for _, spec := range cmd.specs {
+		if spec.positional {
+			continue
+		}
+		if spec.help != "" {
+			fmt.Fprintf(w, "; %s\n", spec.help)
+		}
+		fmt.Fprintf(w, "%s = %s\n", configName(spec), formatConfigValue(p.val(spec.dest)))
+	}
+	fmt.Fprintln(w)
+	for _, sub := range cmd.subcommands {
+		if err := writeConfigCommand(w, p, sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatConfigValue renders v the way WriteConfig writes it: a slice
+// becomes a bracketed array of quoted elements, anything else becomes a
+// single quoted value. An invalid v (e.g. a field behind a still-nil
+// subcommand pointer) renders as an empty value rather than Go's
+// "<invalid reflect.Value>".
+func formatConfigValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return `""`
+	}
+	if v.Kind() == reflect.Slice {
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = fmt.Sprintf("%q", fmt.Sprintf("%v", v.Index(i)))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	}
+	return fmt.Sprintf("%q", fmt.Sprintf("%v", v))
+}
@@ -0,0 +1,79 @@
+package arg
+
+import (
+	"context"
+	"fmt"
+)
+
+// Runner is the interface that a subcommand struct must implement to be
+// dispatched by Parser.Run.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// PreRunner is an optional interface that a subcommand struct (or any of
+// its ancestors) can implement to run setup before the leaf subcommand's
+// Run method is called.
+type PreRunner interface {
+	PreRun(ctx context.Context) error
+}
+
+// PostRunner is an optional interface that a subcommand struct (or any of
+// its ancestors) can implement to run cleanup after the leaf subcommand's
+// Run method returns.
+type PostRunner interface {
+	PostRun(ctx context.Context) error
+}
+
+// Run walks from the root destination struct down to the subcommand
+// selected by the most recent call to Parse, calling PreRun on every
+// ancestor that implements PreRunner (root first), Run on the leaf
+// subcommand, and then PostRun on every ancestor that implements
+// PostRunner (leaf first), mirroring cobra's
+// PersistentPreRun/Run/PostRun ordering. It is opt-in: existing callers
+// that still switch on which subcommand pointer is non-nil are unaffected.
+func (p *Parser) Run(ctx context.Context) error {
+	if p.lastCmd == nil {
+		return fmt.Errorf("Run must be called after Parse")
+	}
+
+	var chain []*command
+	for cmd := p.lastCmd; cmd != nil; cmd = cmd.parent {
+		chain = append(chain, cmd)
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+
+	var preRan []*command
+	for _, cmd := range chain {
+		dest := p.val(cmd.dest)
+		if !dest.IsValid() {
+			continue
+		}
+		if pre, ok := dest.Interface().(PreRunner); ok {
+			if err := pre.PreRun(ctx); err != nil {
+				return fmt.Errorf("%s: %v", cmd.name, err)
+			}
+		}
+		preRan = append(preRan, cmd)
+	}
+
+	leaf := chain[len(chain)-1]
+	dest := p.val(leaf.dest)
+	runner, ok := dest.Interface().(Runner)
+	if !ok {
+		return fmt.Errorf("%s does not implement arg.Runner", leaf.name)
+	}
+	runErr := runner.Run(ctx)
+
+	for i := len(preRan) - 1; i >= 0; i-- {
+		dest := p.val(preRan[i].dest)
+		if post, ok := dest.Interface().(PostRunner); ok {
+			if err := post.PostRun(ctx); err != nil && runErr == nil {
+				runErr = fmt.Errorf("%s: %v", preRan[i].name, err)
+			}
+		}
+	}
+	return runErr
+}
@@ -0,0 +1,87 @@
+// Package docs renders a Markdown reference tree from a go-arg Parser,
+// following the pattern established by cobra's doc generator.
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/marco-m/go-arg"
+)
+
+// WriteMarkdownTree walks p's command tree and writes one Markdown file for
+// the top-level command and one per subcommand into dir, named after the
+// full command path (example.md, example_get.md, example_list.md).
+func WriteMarkdownTree(p *arg.Parser, dir string) error {
+	return writeCommand(p.Describe(), nil, dir)
+}
+
+func writeCommand(cmd arg.CommandSpec, ancestors []string, dir string) error {
+	path := make([]string, len(ancestors)+1)
+	copy(path, ancestors)
+	path[len(ancestors)] = cmd.Name
+	name := strings.Join(path, "_")
+
+	f, err := os.Create(filepath.Join(dir, name+".md"))
+	if err != nil {
+		return fmt.Errorf("error creating markdown file for %s: %v", name, err)
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "## %s\n\n", strings.Join(path, " "))
+	if cmd.Help != "" {
+		fmt.Fprintf(f, "%s\n\n", cmd.Help)
+	}
+
+	var positionals, options []arg.OptionSpec
+	for _, opt := range cmd.Options {
+		if opt.Positional {
+			positionals = append(positionals, opt)
+		} else {
+			options = append(options, opt)
+		}
+	}
+
+	if len(positionals) > 0 {
+		fmt.Fprintf(f, "### Positional arguments\n\n")
+		for _, opt := range positionals {
+			fmt.Fprintf(f, "* `%s` - %s\n", opt.Placeholder, opt.Help)
+		}
+		fmt.Fprintln(f)
+	}
+
+	if len(options) > 0 {
+		fmt.Fprintf(f, "### Options\n\n")
+		for _, opt := range options {
+			fmt.Fprintf(f, "* `--%s`", opt.Long)
+			if opt.Short != "" {
+				fmt.Fprintf(f, ", `-%s`", opt.Short)
+			}
+			fmt.Fprintf(f, " - %s", opt.Help)
+			if opt.Env != "" {
+				fmt.Fprintf(f, " (env: `%s`)", opt.Env)
+			}
+			if opt.Default != "" {
+				fmt.Fprintf(f, " (default: `%s`)", opt.Default)
+			}
+			fmt.Fprintln(f)
+		}
+		fmt.Fprintln(f)
+	}
+
+	if len(cmd.Commands) > 0 {
+		fmt.Fprintf(f, "### Commands\n\n")
+		for _, sub := range cmd.Commands {
+			fmt.Fprintf(f, "* [%s](%s_%s.md) - %s\n", sub.Name, name, sub.Name, sub.Help)
+		}
+	}
+
+	for _, sub := range cmd.Commands {
+		if err := writeCommand(sub, path, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,11 +1,24 @@
 package arg
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 )
 
+func init() {
+	RegisterCompletionFunc("hosts", func(args []string, toComplete string) []string {
+		var out []string
+		for _, h := range []string{"db1.example.com", "db2.example.com", "web1.example.com"} {
+			if strings.HasPrefix(h, toComplete) {
+				out = append(out, h)
+			}
+		}
+		return out
+	})
+}
+
 func split(s string) []string {
 	return strings.Split(s, " ")
 }
@@ -299,3 +312,189 @@ func Example_subcommand() {
 	// output:
 	// commit requested with message "what-this-commit-is-about"
 }
+
+type pullCmd struct {
+	Remote string `arg:"positional"`
+}
+
+func (c *pullCmd) PreRun(ctx context.Context) error {
+	fmt.Println("connecting to", c.Remote)
+	return nil
+}
+
+func (c *pullCmd) Run(ctx context.Context) error {
+	fmt.Println("pulling from", c.Remote)
+	return nil
+}
+
+func (c *pullCmd) PostRun(ctx context.Context) error {
+	fmt.Println("done")
+	return nil
+}
+
+// This example demonstrates dispatching to a subcommand's Run method
+// instead of switching on which subcommand pointer is non-nil.
+func Example_subcommandDispatch() {
+	// These are the args you would pass in on the command line
+	os.Args = split("./example pull origin")
+
+	var args struct {
+		Pull *pullCmd `arg:"subcommand:pull"`
+	}
+
+	p := MustParse(&args)
+	if err := p.Run(context.Background()); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// output:
+	// connecting to origin
+	// pulling from origin
+	// done
+}
+
+// This example demonstrates dynamic shell completion: Complete intercepts
+// the hidden __complete subcommand emitted by the scripts GenBashCompletion
+// and friends generate, and dispatches to the CompleteFunc registered for
+// --host's complete:hosts tag.
+func Example_complete() {
+	var args struct {
+		Host string `arg:"--host,complete:hosts"`
+	}
+	p, err := NewParser(Config{Program: "example"}, &args)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	words, ok := p.Complete(split("__complete --host db"))
+	fmt.Println(ok, words)
+	// output: true [db1.example.com db2.example.com]
+}
+
+// This example demonstrates WriteConfig and Config.Files: WriteConfig
+// produces a file that Config.Files can read straight back in via
+// NewParser, with CLI flags still taking precedence over whatever the
+// config file set.
+func Example_config() {
+	var written struct {
+		Host string `help:"database host"`
+		Port int
+	}
+	written.Host = "localhost"
+	written.Port = 5432
+
+	p, err := NewParser(Config{}, &written)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	var buf strings.Builder
+	if err := p.WriteConfig(&buf); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	f, err := os.CreateTemp("", "example-config-*.ini")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(buf.String())
+	f.Close()
+
+	os.Args = split("./example --port 5433")
+	var loaded struct {
+		Host string
+		Port int
+	}
+	loadedParser, err := NewParser(Config{Files: []string{f.Name()}}, &loaded)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	if err := loadedParser.Parse(flags()); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(loaded.Host, loaded.Port)
+	// output: localhost 5433
+}
+
+// This example demonstrates Describe, the introspection API the manpage
+// and docs subpackages use to render documentation without reaching into
+// the parser's unexported state.
+func Example_describe() {
+	type getCmd struct {
+		Item string `arg:"positional" help:"item to fetch"`
+	}
+	var args struct {
+		Verbose bool    `help:"enable verbose output"`
+		Get     *getCmd `arg:"subcommand" help:"fetch an item and print it"`
+	}
+
+	p, err := NewParser(Config{Program: "example"}, &args)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	spec := p.Describe()
+	fmt.Println(spec.Name, len(spec.Options), len(spec.Commands))
+	fmt.Println(spec.Commands[0].Name, spec.Commands[0].Options[0].Placeholder)
+	// output:
+	// example 1 1
+	// get ITEM
+}
+
+// This example demonstrates hidden and deprecated flags: a hidden flag is
+// omitted from --help entirely, while a deprecated one still works but
+// prints a warning to stderr the first time it's used.
+func Example_hiddenAndDeprecatedFlags() {
+	os.Args = split("./example --old-format csv")
+
+	var args struct {
+		Format string `arg:"--old-format,deprecated:use --format instead" help:"output format"`
+		Secret string `arg:"--secret,hidden"`
+	}
+
+	// This is only necessary when running inside golang's runnable example harness
+	osExit = func(int) {}
+	stderr = os.Stdout
+
+	MustParse(&args)
+	fmt.Println(args.Format)
+
+	// output:
+	// warning: --old-format is deprecated: use --format instead
+	// csv
+}
+
+// This example demonstrates "did you mean?" suggestions for a mistyped
+// flag, including a flag inherited from a parent command.
+func Example_suggestions() {
+	os.Args = split("./example get --verbos")
+
+	type getCmd struct {
+		Item string `arg:"positional"`
+	}
+	var args struct {
+		Verbose bool    `arg:"--verbose"`
+		Get     *getCmd `arg:"subcommand"`
+	}
+
+	// This is only necessary when running inside golang's runnable example harness
+	osExit = func(int) {}
+	stderr = os.Stdout
+
+	MustParse(&args)
+
+	// output:
+	// Usage: example get ITEM
+	// error: unknown argument --verbos
+	//
+	// Did you mean?
+	// 	--verbose
+}
@@ -0,0 +1,54 @@
+package arg
+
+// OptionSpec describes a single flag or positional argument for external
+// tools (such as the manpage and docs subpackages) that need to render help
+// content without reaching into the parser's unexported state.
+type OptionSpec struct {
+	Long        string
+	Short       string
+	Help        string
+	Env         string
+	Default     string
+	Placeholder string
+	Positional  bool
+	Required    bool
+	Boolean     bool
+	Multiple    bool
+}
+
+// CommandSpec describes a command or subcommand, including its own options
+// and any nested subcommands.
+type CommandSpec struct {
+	Name     string
+	Help     string
+	Options  []OptionSpec
+	Commands []CommandSpec
+}
+
+// Describe returns a description of this parser's full command tree,
+// suitable for driving documentation generators.
+func (p *Parser) Describe() CommandSpec {
+	return describeCommand(p.cmd)
+}
+
+func describeCommand(cmd *command) CommandSpec {
+	out := CommandSpec{Name: cmd.name, Help: cmd.help}
+	for _, spec := range cmd.specs {
+		out.Options = append(out.Options, OptionSpec{
+			Long:        spec.long,
+			Short:       spec.short,
+			Help:        spec.help,
+			Env:         spec.env,
+			Default:     spec.defaultVal,
+			Placeholder: placeholderFor(spec),
+			Positional:  spec.positional,
+			Required:    spec.required,
+			Boolean:     spec.boolean,
+			Multiple:    spec.multiple,
+		})
+	}
+	for _, sub := range cmd.subcommands {
+		out.Commands = append(out.Commands, describeCommand(sub))
+	}
+	return out
+}
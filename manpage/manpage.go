@@ -0,0 +1,127 @@
+// Package manpage renders Unix man pages (roff) from a go-arg Parser,
+// following the pattern established by go-flags' man generator and cobra's
+// doc generator.
+package manpage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/marco-m/go-arg"
+)
+
+// Header supplies the man page metadata that isn't part of the parser
+// itself.
+type Header struct {
+	Section string // man section, e.g. "1"
+	Source  string // e.g. "example 1.0"
+	Manual  string // e.g. "User Commands"
+}
+
+// Write renders a single man page for p's top-level command to w, following
+// the exact signature a caller of this package should expect: one Parser, one
+// Writer, one page. Subcommands are listed in a COMMANDS section, as SEE ALSO
+// cross-references, but are not themselves expanded onto the page; use
+// WriteTree to additionally render one page per subcommand.
+func Write(p *arg.Parser, w io.Writer, hdr Header) error {
+	return renderCommand(w, p.Describe(), nil, hdr)
+}
+
+// WriteTree walks p's command tree and writes one man page per subcommand
+// into dir, named after the full command path and hdr.Section
+// (example-get.1, example-list.1). A parser with no subcommands still
+// produces exactly one file, dir/name.hdr.Section.
+func WriteTree(p *arg.Parser, dir string, hdr Header) error {
+	return writeCommandTree(p.Describe(), nil, dir, hdr)
+}
+
+func writeCommandTree(cmd arg.CommandSpec, ancestors []string, dir string, hdr Header) error {
+	// copy the slice to avoid aliasing ancestors' backing array across siblings
+	path := make([]string, len(ancestors)+1)
+	copy(path, ancestors)
+	path[len(ancestors)] = cmd.Name
+	name := strings.Join(path, "-")
+
+	f, err := os.Create(filepath.Join(dir, name+"."+hdr.Section))
+	if err != nil {
+		return fmt.Errorf("error creating man page for %s: %v", name, err)
+	}
+	err = renderCommand(f, cmd, ancestors, hdr)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range cmd.Commands {
+		if err := writeCommandTree(sub, path, dir, hdr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderCommand writes the man page for a single cmd, found at ancestors in
+// the command tree, to w.
+func renderCommand(w io.Writer, cmd arg.CommandSpec, ancestors []string, hdr Header) error {
+	path := make([]string, len(ancestors)+1)
+	copy(path, ancestors)
+	path[len(ancestors)] = cmd.Name
+	name := strings.Join(path, "-")
+
+	fmt.Fprintf(w, ".TH %s %q %q %q\n", strings.ToUpper(name), hdr.Section, hdr.Source, hdr.Manual)
+
+	fmt.Fprintf(w, ".SH NAME\n%s\n", name)
+
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B %s\n", strings.Join(path, " "))
+	for _, opt := range cmd.Options {
+		// .br forces each option onto its own line; without it, roff's
+		// default fill mode would run every option together on one line
+		fmt.Fprint(w, ".br\n")
+		if opt.Positional {
+			fmt.Fprintf(w, ".I %s\n", opt.Placeholder)
+		} else {
+			fmt.Fprintf(w, "[\\fB--%s\\fR]\n", opt.Long)
+		}
+	}
+
+	if len(cmd.Options) > 0 {
+		fmt.Fprintf(w, ".SH OPTIONS\n")
+		for _, opt := range cmd.Options {
+			writeOption(w, opt)
+		}
+	}
+
+	if len(cmd.Commands) > 0 {
+		fmt.Fprintf(w, ".SH COMMANDS\n")
+		for _, sub := range cmd.Commands {
+			fmt.Fprintf(w, ".TP\n.B %s\n%s\n", sub.Name, sub.Help)
+		}
+		fmt.Fprintf(w, ".SH SEE ALSO\n")
+		for _, sub := range cmd.Commands {
+			fmt.Fprintf(w, "%s-%s(%s)\n", name, sub.Name, hdr.Section)
+		}
+	}
+
+	return nil
+}
+
+func writeOption(w io.Writer, opt arg.OptionSpec) {
+	if opt.Positional {
+		fmt.Fprintf(w, ".TP\n.I %s\n%s\n", opt.Placeholder, opt.Help)
+		return
+	}
+	left := "\\fB--" + opt.Long + "\\fR"
+	if !opt.Boolean {
+		left += " " + opt.Placeholder
+	}
+	if opt.Short != "" {
+		left += ", \\fB-" + opt.Short + "\\fR"
+	}
+	fmt.Fprintf(w, ".TP\n%s\n%s\n", left, opt.Help)
+	if opt.Env != "" {
+		fmt.Fprintf(w, "Also settable via the %s environment variable.\n", opt.Env)
+	}
+}
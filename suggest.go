@@ -0,0 +1,139 @@
+package arg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Suggestions returns the long flag names accepted at the subcommand scope
+// that was active the last time Parse was called -- which, just like
+// process()'s own matching, includes every ancestor command's flags, not
+// just lastCmd's own -- plus lastCmd's subcommand names, sorted by edit
+// distance to unknown and then lexicographically. Candidates farther than
+// max(p.SuggestionsMinDistance, len(unknown)/3) away are omitted.
+func (p *Parser) Suggestions(unknown string) []string {
+	if p.DisableSuggestions || p.lastCmd == nil {
+		return nil
+	}
+	unknown = strings.TrimLeft(unknown, "-")
+
+	maxDist := p.SuggestionsMinDistance
+	if d := len(unknown) / 3; d > maxDist {
+		maxDist = d
+	}
+
+	type candidate struct {
+		name string
+		dist int
+	}
+	var candidates []candidate
+	for cmd := p.lastCmd; cmd != nil; cmd = cmd.parent {
+		for _, spec := range cmd.specs {
+			if spec.positional || spec.hidden {
+				continue
+			}
+			if d := levenshtein(unknown, spec.long); d <= maxDist {
+				candidates = append(candidates, candidate{"--" + spec.long, d})
+			}
+		}
+	}
+	for _, sub := range p.lastCmd.subcommands {
+		if d := levenshtein(unknown, sub.name); d <= maxDist {
+			candidates = append(candidates, candidate{sub.name, d})
+		}
+	}
+
+	// a subcommand can redeclare an ancestor's flag name, so dedupe by name,
+	// keeping the closest match seen
+	seen := make(map[string]int, len(candidates))
+	deduped := candidates[:0]
+	for _, c := range candidates {
+		if best, ok := seen[c.name]; ok {
+			if c.dist < best {
+				seen[c.name] = c.dist
+			}
+			continue
+		}
+		seen[c.name] = c.dist
+		deduped = append(deduped, c)
+	}
+	for i := range deduped {
+		deduped[i].dist = seen[deduped[i].name]
+	}
+	candidates = deduped
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].dist != candidates[j].dist {
+			return candidates[i].dist < candidates[j].dist
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	out := make([]string, len(candidates))
+	for i, c := range candidates {
+		out[i] = c.name
+	}
+	return out
+}
+
+// FormatSuggestions renders the error message msg and its suggestions in the style
+// cobra uses for its closest-match helper, e.g.:
+//
+//	error: unknown argument --optmize
+//
+//	Did you mean?
+//		--optimize
+func FormatSuggestions(msg string, suggestions []string) string {
+	return "error: " + msg + suggestionBlock(suggestions)
+}
+
+// suggestionBlock renders suggestions as a "Did you mean?" block to append
+// to an existing error message, or "" if there are no suggestions.
+func suggestionBlock(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprint(&b, "\n\nDid you mean?\n")
+	for _, s := range suggestions {
+		fmt.Fprintf(&b, "\t%s\n", s)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// levenshtein computes the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			del := prev[j] + 1
+			ins := cur[j-1] + 1
+			sub := prev[j-1] + cost
+			cur[j] = min3(del, ins, sub)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}